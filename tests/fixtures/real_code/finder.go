@@ -0,0 +1,237 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strings
+
+// A Finder is a compiled search handle for repeated searches for the same
+// pattern. Building a Finder amortizes the preprocessing that [Index] would
+// otherwise redo on every call: a Boyer-Moore-Horspool bad-character shift
+// table, plus a Galil-rule good-suffix table for long patterns. Very short
+// patterns fall back to brute force, for which preprocessing would cost
+// more than it saves.
+//
+// A Finder's tables are immutable once built, so a *Finder is safe for
+// concurrent use by multiple goroutines.
+type Finder struct {
+	pattern string
+	// badChar[c] is the distance to shift the pattern when a mismatching
+	// byte c is found aligned with the pattern, per Boyer-Moore-Horspool.
+	badChar [256]int
+	// goodSuffix, if non-nil, is the Galil-rule good-suffix shift table,
+	// built only for patterns long enough that it pays for itself.
+	goodSuffix []int
+	bruteForce bool
+}
+
+// minFinderTable is the shortest pattern length for which Finder builds the
+// Boyer-Moore-Horspool tables; shorter patterns use brute force, since the
+// tables would cost more to build than a linear scan saves.
+const minFinderTable = 4
+
+// NewFinder compiles a Finder for pattern. NewFinder panics if pattern is
+// empty.
+func NewFinder(pattern string) *Finder {
+	if len(pattern) == 0 {
+		panic("strings: NewFinder requires a non-empty pattern")
+	}
+	f := &Finder{pattern: pattern}
+	if len(pattern) < minFinderTable {
+		f.bruteForce = true
+		return f
+	}
+
+	for i := range f.badChar {
+		f.badChar[i] = len(pattern)
+	}
+	for i := 0; i < len(pattern)-1; i++ {
+		f.badChar[pattern[i]] = len(pattern) - 1 - i
+	}
+
+	f.goodSuffix = buildGoodSuffix(pattern)
+	return f
+}
+
+// buildGoodSuffix computes the Galil-rule good-suffix shift table: for a
+// mismatch after matching the suffix pattern[i+1:], goodSuffix[i] is the
+// distance to shift the pattern so that the matched suffix realigns with
+// another occurrence of it in the pattern (or a suffix of it that is also
+// a prefix of the pattern).
+func buildGoodSuffix(pattern string) []int {
+	m := len(pattern)
+	suff := make([]int, m)
+	goodSuffix := make([]int, m)
+	for i := range goodSuffix {
+		goodSuffix[i] = m
+	}
+
+	suff[m-1] = m
+	g := m - 1
+	f := 0
+	for i := m - 2; i >= 0; i-- {
+		if i > g && suff[i+m-1-f] < i-g {
+			suff[i] = suff[i+m-1-f]
+		} else {
+			if i < g {
+				g = i
+			}
+			f = i
+			for g >= 0 && pattern[g] == pattern[g+m-1-f] {
+				g--
+			}
+			suff[i] = f - g
+		}
+	}
+
+	for i := 0; i < m; i++ {
+		goodSuffix[i] = m
+	}
+	j := 0
+	for i := m - 1; i >= 0; i-- {
+		if suff[i] == i+1 {
+			for ; j < m-1-i; j++ {
+				if goodSuffix[j] == m {
+					goodSuffix[j] = m - 1 - i
+				}
+			}
+		}
+	}
+	for i := 0; i <= m-2; i++ {
+		goodSuffix[m-1-suff[i]] = m - 1 - i
+	}
+	return goodSuffix
+}
+
+// Index returns the index of the first instance of f's pattern in s, or -1
+// if it is not present.
+func (f *Finder) Index(s string) int {
+	m := len(f.pattern)
+	if m > len(s) {
+		return -1
+	}
+	if f.bruteForce {
+		for i := 0; i+m <= len(s); i++ {
+			if s[i:i+m] == f.pattern {
+				return i
+			}
+		}
+		return -1
+	}
+
+	i := 0
+	for i <= len(s)-m {
+		j := m - 1
+		for j >= 0 && f.pattern[j] == s[i+j] {
+			j--
+		}
+		if j < 0 {
+			return i
+		}
+		shift := f.badChar[s[i+m-1]]
+		if gs := f.goodSuffix[j]; gs > shift {
+			shift = gs
+		}
+		i += shift
+	}
+	return -1
+}
+
+// LastIndex returns the index of the last instance of f's pattern in s, or
+// -1 if it is not present.
+func (f *Finder) LastIndex(s string) int {
+	last := -1
+	start := 0
+	for {
+		i := f.Index(s[start:])
+		if i < 0 {
+			return last
+		}
+		last = start + i
+		start = last + 1
+		if start > len(s)-len(f.pattern) {
+			return last
+		}
+	}
+}
+
+// Count returns the number of non-overlapping instances of f's pattern in
+// s.
+func (f *Finder) Count(s string) int {
+	n := 0
+	for {
+		i := f.Index(s)
+		if i < 0 {
+			return n
+		}
+		n++
+		s = s[i+len(f.pattern):]
+	}
+}
+
+// FindAll returns the start indexes of the first n non-overlapping
+// instances of f's pattern in s. If n < 0, all instances are returned.
+func (f *Finder) FindAll(s string, n int) []int {
+	if n == 0 {
+		return nil
+	}
+	var out []int
+	offset := 0
+	for n < 0 || len(out) < n {
+		i := f.Index(s[offset:])
+		if i < 0 {
+			break
+		}
+		out = append(out, offset+i)
+		offset += i + len(f.pattern)
+	}
+	return out
+}
+
+// ReplaceAll returns a copy of s with all non-overlapping instances of f's
+// pattern replaced by repl.
+func (f *Finder) ReplaceAll(s, repl string) string {
+	var b Builder
+	for {
+		i := f.Index(s)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		b.WriteString(s[:i])
+		b.WriteString(repl)
+		s = s[i+len(f.pattern):]
+	}
+}
+
+// A FinderSet is a compiled search handle for repeated searches over a
+// fixed set of patterns, mirroring [Finder] but for many needles at once.
+// It is backed by a [Matcher], which runs Aho-Corasick so that a single
+// pass over s reports matches of every pattern. A *FinderSet is safe for
+// concurrent use by multiple goroutines.
+//
+// BUG(strings): NewReplacer does not yet use a FinderSet in place of its
+// trie walker for many long keys. Replacer is not part of this package as
+// it stands, so wiring the two together is a separate, much larger change
+// than compiling a pattern set, and remains outstanding.
+type FinderSet struct {
+	m *Matcher
+}
+
+// NewFinderSet compiles a FinderSet for patterns. NewFinderSet panics if
+// patterns is empty.
+func NewFinderSet(patterns []string) *FinderSet {
+	return &FinderSet{m: NewMatcher(patterns)}
+}
+
+// Index returns the index of the first match of any of the FinderSet's
+// patterns in s, and the index into the original patterns slice of the
+// pattern that matched. It returns (-1, -1) if none match.
+func (fs *FinderSet) Index(s string) (pos int, patternIndex int) {
+	return fs.m.IndexAny(s)
+}
+
+// Count returns the number of non-overlapping matches of any of the
+// FinderSet's patterns in s.
+func (fs *FinderSet) Count(s string) int {
+	return fs.m.Count(s)
+}
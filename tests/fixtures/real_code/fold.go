@@ -0,0 +1,225 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strings
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// IndexFold returns the index of the first instance of substr in s under
+// Unicode simple case folding, or -1 if substr is not present. Unlike
+// ToLower(s) == ToLower(substr) comparisons, IndexFold performs no
+// allocation and folds independently of locale, so that e.g. "ß" matches
+// "ss" only to the extent SimpleFold relates them (it does not, since
+// simple folding never merges multiple runes into one).
+//
+// Because fold-equal substrings may differ in byte length (for example,
+// "k" and the Kelvin sign "K" U+212A fold equal but are not the same
+// length in UTF-8), the returned value is only a byte offset into s: the
+// length of the match must be found by folding forward from that offset,
+// comparing runes of s against substr until substr is used up.
+func IndexFold(s, substr string) int {
+	if len(substr) == 0 {
+		return 0
+	}
+	// The ASCII fast path is only valid when s is ASCII too: a non-ASCII
+	// rune in s (e.g. the Kelvin sign U+212A, which simple-folds to 'k')
+	// can still fold-equal an ASCII substr, and byte-at-a-time scanning
+	// would miss it.
+	if isASCII(substr) && isASCII(s) {
+		return indexFoldASCII(s, substr)
+	}
+	return indexFoldUnicode(s, substr, false)
+}
+
+// LastIndexFold returns the index of the last instance of substr in s
+// under Unicode simple case folding, or -1 if substr is not present. See
+// [IndexFold] for how byte offsets relate to fold-equal substrings of
+// differing length.
+func LastIndexFold(s, substr string) int {
+	if len(substr) == 0 {
+		return len(s)
+	}
+	if isASCII(substr) && isASCII(s) {
+		return lastIndexFoldASCII(s, substr)
+	}
+	return indexFoldUnicode(s, substr, true)
+}
+
+// ContainsFold reports whether substr is within s under Unicode simple
+// case folding. It is the substring companion to [EqualFold].
+func ContainsFold(s, substr string) bool {
+	return IndexFold(s, substr) >= 0
+}
+
+// CountFold counts the number of non-overlapping instances of substr in s
+// under Unicode simple case folding.
+func CountFold(s, substr string) int {
+	if len(substr) == 0 {
+		return utf8.RuneCountInString(s) + 1
+	}
+	n := 0
+	for {
+		i := IndexFold(s, substr)
+		if i < 0 {
+			return n
+		}
+		n++
+		width, _ := foldedMatchWidth(s[i:], substr)
+		s = s[i+width:]
+	}
+}
+
+// ReplaceAllFold returns a copy of s with all non-overlapping instances of
+// substr, matched under Unicode simple case folding, replaced by repl.
+// The matched text is replaced verbatim by repl; the original casing of
+// the matched run in s is not preserved.
+func ReplaceAllFold(s, substr, repl string) string {
+	if len(substr) == 0 {
+		return s
+	}
+	var b Builder
+	for {
+		i := IndexFold(s, substr)
+		if i < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		width, _ := foldedMatchWidth(s[i:], substr)
+		b.WriteString(s[:i])
+		b.WriteString(repl)
+		s = s[i+width:]
+	}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// asciiFoldByte folds an ASCII byte to its lower-case form; non-letters are
+// returned unchanged.
+func asciiFoldByte(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// indexFoldASCII finds substr (known ASCII) in s under ASCII case folding
+// using a folded Rabin-Karp rolling hash, avoiding any allocation.
+func indexFoldASCII(s, substr string) int {
+	n := len(substr)
+	if n > len(s) {
+		return -1
+	}
+	var hashss, pow uint32
+	for i := 0; i < n; i++ {
+		hashss = hashss*primeRK + uint32(asciiFoldByte(substr[i]))
+		if i == 0 {
+			pow = 1
+		} else {
+			pow *= primeRK
+		}
+	}
+	var h uint32
+	for i := 0; i < n; i++ {
+		h = h*primeRK + uint32(asciiFoldByte(s[i]))
+	}
+	i := 0
+	for {
+		if h == hashss && foldEqualASCII(s[i:i+n], substr) {
+			return i
+		}
+		if i+n >= len(s) {
+			return -1
+		}
+		h *= primeRK
+		h += uint32(asciiFoldByte(s[i+n]))
+		h -= pow * primeRK * uint32(asciiFoldByte(s[i]))
+		i++
+	}
+}
+
+// lastIndexFoldASCII is indexFoldASCII's mirror, scanning candidate
+// positions from the end of s.
+func lastIndexFoldASCII(s, substr string) int {
+	n := len(substr)
+	if n > len(s) {
+		return -1
+	}
+	for i := len(s) - n; i >= 0; i-- {
+		if foldEqualASCII(s[i:i+n], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func foldEqualASCII(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := 0; i < len(a); i++ {
+		if asciiFoldByte(a[i]) != asciiFoldByte(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// indexFoldUnicode finds substr in s under Unicode simple case folding by
+// decoding runes from both sides and comparing via unicode.SimpleFold
+// cycles, rather than normalizing into an intermediate allocation. When
+// last is true it returns the last match instead of the first.
+func indexFoldUnicode(s, substr string, last bool) int {
+	found := -1
+	for i := range s {
+		if _, ok := foldedMatchWidth(s[i:], substr); ok {
+			if !last {
+				return i
+			}
+			found = i
+		}
+	}
+	return found
+}
+
+// foldedMatchWidth reports whether substr matches a fold-equal prefix of s,
+// and if so the byte width of that prefix (which may differ from
+// len(substr)).
+func foldedMatchWidth(s, substr string) (width int, ok bool) {
+	orig := s
+	for _, r := range substr {
+		if len(s) == 0 {
+			return 0, false
+		}
+		sr, size := utf8.DecodeRuneInString(s)
+		if !runeFoldEqual(sr, r) {
+			return 0, false
+		}
+		s = s[size:]
+	}
+	return len(orig) - len(s), true
+}
+
+// runeFoldEqual reports whether a and b are equal under Unicode simple
+// case folding.
+func runeFoldEqual(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
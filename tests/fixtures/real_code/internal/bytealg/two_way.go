@@ -0,0 +1,194 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package bytealg provides low-level byte and string routines used by
+// package strings and package bytes.
+package bytealg
+
+// MinTwoWay is the minimum pattern length at which the two-way algorithm is
+// used in preference to Rabin-Karp. Below this length the fixed per-call
+// overhead of computing the critical factorization dominates.
+const MinTwoWay = 16
+
+// twoWayFactorization holds the precomputed critical factorization of a
+// pattern, so that IndexTwoWayString can run in O(n) time and O(1) extra
+// space regardless of how many times the pattern is searched for.
+type twoWayFactorization struct {
+	pattern string
+	suffix  int  // start of the suffix v in the critical factorization p = uv
+	period  int  // period of v (and, by construction, of p from suffix-period on)
+	short   bool // whether p[:suffix] == p[period:period+suffix], so period is a period of all of p (p is "periodic")
+}
+
+// maxSuffix returns the position of the start of the maximal suffix of s
+// under the order relation given by less, along with the period of that
+// suffix. less should be the function that reports whether a < b for the
+// algorithm's two passes: one with the usual byte order, one with it
+// reversed; the true critical factorization uses whichever split position is
+// larger.
+func maxSuffix(s string, less func(a, b byte) bool) (pos, period int) {
+	ms := -1 // position of the current candidate maximal suffix, minus one
+	j := 0
+	k := 1
+	p := 1
+	for j+k < len(s) {
+		a := s[j+k]
+		b := s[ms+k]
+		if less(a, b) {
+			j += k
+			k = 1
+			p = j - ms
+		} else if a == b {
+			if k != p {
+				k++
+			} else {
+				j += p
+				k = 1
+			}
+		} else {
+			ms = j
+			j = ms + 1
+			k = 1
+			p = 1
+		}
+	}
+	return ms + 1, p
+}
+
+// newTwoWayFactorization computes the critical factorization p = uv of
+// pattern required by the two-way string matching algorithm of Crochemore
+// and Perrin: the maximal suffix is computed twice, once under < and once
+// under >, and the factorization with the larger starting position is kept,
+// since that is the one whose period is also the period of p from that
+// point on.
+// A factorization is "short-period" (often just called periodic) when u
+// occurs again period bytes into p, i.e. p[:suffix] ==
+// p[period:period+suffix], so that v's period is a period of all of p. That is precisely the case in which sliding the
+// window forward by period, after a full match of p, is known to leave a
+// prefix of p still matched against the text — letting the next round skip
+// re-checking it via memory. Long-period patterns have no such guarantee
+// and must shift by a full window (max(len(u), len(v)) + 1) instead.
+func newTwoWayFactorization(pattern string) twoWayFactorization {
+	i1, p1 := maxSuffix(pattern, func(a, b byte) bool { return a < b })
+	i2, p2 := maxSuffix(pattern, func(a, b byte) bool { return a > b })
+
+	suffix, period := i1, p1
+	if i2 > i1 {
+		suffix, period = i2, p2
+	}
+
+	short := period+suffix <= len(pattern) && pattern[:suffix] == pattern[period:period+suffix]
+	return twoWayFactorization{pattern: pattern, suffix: suffix, period: period, short: short}
+}
+
+// IndexTwoWayString returns the index of the first occurrence of pattern in
+// s using the Crochemore-Perrin two-way algorithm, or -1 if pattern is not
+// present. It runs in O(len(s)) time with O(1) extra space and no
+// preprocessing tables, making it a safe default against adversarial input
+// unlike Rabin-Karp's hash collisions or brute force's O(nm) worst case.
+func IndexTwoWayString(s, pattern string) int {
+	if len(pattern) == 0 {
+		return 0
+	}
+	if len(pattern) > len(s) {
+		return -1
+	}
+	f := newTwoWayFactorization(pattern)
+	return f.index(s)
+}
+
+// LastIndexTwoWayString returns the index of the last occurrence of pattern
+// in s, or -1 if pattern is not present. It is implemented by repeated
+// forward two-way searches, each starting one byte past the previous match
+// with no memory carried over from it. When matches overlap heavily (for
+// example, a run of one byte searched for within a longer run of that
+// byte), every search compares the whole pattern again, so the total time
+// is O(len(s)*len(pattern)) rather than linear.
+func LastIndexTwoWayString(s, pattern string) int {
+	if len(pattern) == 0 {
+		return len(s)
+	}
+	if len(pattern) > len(s) {
+		return -1
+	}
+	f := newTwoWayFactorization(pattern)
+	last := -1
+	start := 0
+	for {
+		i := f.index(s[start:])
+		if i < 0 {
+			return last
+		}
+		last = start + i
+		start = last + 1
+		if start > len(s)-len(pattern) {
+			return last
+		}
+	}
+}
+
+// index runs the two-way search of f.pattern against s, starting at 0.
+//
+// The pattern p = uv is matched by first comparing v against s left to
+// right; on a mismatch at offset k within v, the window is shifted by k+1
+// and matching restarts from v. On a full match of v, u is compared against
+// s right to left.
+//
+// What happens next depends on whether the factorization is short-period
+// (f.short): if so, the window can safely be shifted by exactly the period,
+// and memory records how many leading bytes of v are already known to
+// match from the previous, overlapping window, so the next comparison of v
+// can skip them. If the period is not short, no such overlap is guaranteed,
+// so the window is shifted by a full max(|u|, |v|)+1 and memory is reset;
+// this is what the Crochemore-Perrin algorithm calls the two-way search's
+// periodic/non-periodic case split, and both bound the algorithm to at most
+// 2*len(s)-len(pattern) comparisons.
+func (f *twoWayFactorization) index(s string) int {
+	p := f.pattern
+	ell := f.suffix
+	period := f.period
+
+	// The shift to apply after v matches in full but u does not, and the
+	// value given to memory, differ by case; the non-periodic case has no memory at all.
+	shift := period
+	if !f.short {
+		if ell > len(p)-ell {
+			shift = ell + 1
+		} else {
+			shift = len(p) - ell + 1
+		}
+	}
+
+	pos := 0
+	memory := 0
+	for pos <= len(s)-len(p) {
+		// Match v left to right, starting past whatever is remembered as
+		// already matched from the previous window.
+		i := max(ell, memory)
+		for i < len(p) && p[i] == s[pos+i] {
+			i++
+		}
+		if i < len(p) {
+			pos += i - ell + 1
+			memory = 0
+			continue
+		}
+
+		// v matched in full; match u right to left.
+		j := ell - 1
+		for j >= memory && p[j] == s[pos+j] {
+			j--
+		}
+		if j < memory {
+			return pos
+		}
+		pos += shift
+		if f.short {
+			memory = len(p) - period
+		} else {
+			memory = 0
+		}
+	}
+	return -1
+}
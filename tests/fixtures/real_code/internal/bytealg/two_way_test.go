@@ -0,0 +1,98 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package bytealg_test
+
+import (
+	"internal/bytealg"
+	"strings"
+	"testing"
+)
+
+// bruteForceIndex and bruteForceLastIndex are deliberately independent of
+// both bytealg and strings.Index/LastIndex, which (via bytealg.MinTwoWay)
+// route long patterns straight into the code under test. Comparing against
+// strings.Index for a pattern long enough to hit that threshold would just
+// compare the implementation against itself.
+func bruteForceIndex(s, pattern string) int {
+	if len(pattern) == 0 {
+		return 0
+	}
+	for i := 0; i+len(pattern) <= len(s); i++ {
+		if s[i:i+len(pattern)] == pattern {
+			return i
+		}
+	}
+	return -1
+}
+
+func bruteForceLastIndex(s, pattern string) int {
+	if len(pattern) == 0 {
+		return len(s)
+	}
+	for i := len(s) - len(pattern); i >= 0; i-- {
+		if s[i:i+len(pattern)] == pattern {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestIndexTwoWayString(t *testing.T) {
+	cases := []struct{ s, pattern string }{
+		{"", ""},
+		{"abc", ""},
+		{"", "abc"},
+		{"hello world", "world"},
+		{"hello world", "xyz"},
+		{strings.Repeat("a", 64) + "b", strings.Repeat("a", 63) + "b"},
+		{strings.Repeat("a", 64), strings.Repeat("a", 64) + "b"},
+		{strings.Repeat("ab", 40), strings.Repeat("ab", 20) + "ac"},
+	}
+	for _, c := range cases {
+		got := bytealg.IndexTwoWayString(c.s, c.pattern)
+		want := bruteForceIndex(c.s, c.pattern)
+		if got != want {
+			t.Errorf("IndexTwoWayString(%q, %q) = %d, want %d", c.s, c.pattern, got, want)
+		}
+	}
+}
+
+func TestLastIndexTwoWayString(t *testing.T) {
+	cases := []struct{ s, pattern string }{
+		{"", ""},
+		{"abc", ""},
+		{"", "abc"},
+		{"abcabcabc", "abc"},
+		{"hello world hello", "hello"},
+		{strings.Repeat("ab", 40), strings.Repeat("ab", 20)},
+	}
+	for _, c := range cases {
+		got := bytealg.LastIndexTwoWayString(c.s, c.pattern)
+		want := bruteForceLastIndex(c.s, c.pattern)
+		if got != want {
+			t.Errorf("LastIndexTwoWayString(%q, %q) = %d, want %d", c.s, c.pattern, got, want)
+		}
+	}
+}
+
+// FuzzIndexTwoWayString compares the two-way implementation against a
+// brute-force oracle across random and adversarial inputs, including
+// periodic patterns like "aaa...aab" in "aaa...aaa" that are worst cases
+// for naive brute force and pathological for some hash-based matchers.
+func FuzzIndexTwoWayString(f *testing.F) {
+	f.Add(strings.Repeat("a", 32)+"b", strings.Repeat("a", 16)+"b")
+	f.Add(strings.Repeat("a", 32), strings.Repeat("a", 33))
+	f.Add("mississippi river mississippi", "ississippi")
+	f.Add("", "")
+
+	f.Fuzz(func(t *testing.T, s, pattern string) {
+		if got, want := bytealg.IndexTwoWayString(s, pattern), bruteForceIndex(s, pattern); got != want {
+			t.Fatalf("IndexTwoWayString(%q, %q) = %d, want %d", s, pattern, got, want)
+		}
+		if got, want := bytealg.LastIndexTwoWayString(s, pattern), bruteForceLastIndex(s, pattern); got != want {
+			t.Fatalf("LastIndexTwoWayString(%q, %q) = %d, want %d", s, pattern, got, want)
+		}
+	})
+}
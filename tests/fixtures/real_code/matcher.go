@@ -0,0 +1,262 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strings
+
+// A Match records where a pattern was found by a [Matcher].
+type Match struct {
+	Start, End int // byte offsets into the searched string
+	Pattern    int // index into the patterns passed to NewMatcher
+}
+
+// MatcherOpts configures the behavior of a [Matcher].
+type MatcherOpts struct {
+	// FoldCase makes the matcher compare patterns against s
+	// ASCII-case-insensitively.
+	FoldCase bool
+
+	// LongestLeftmost suppresses overlapping matches, keeping only the
+	// longest match starting at each position a match is found.
+	LongestLeftmost bool
+}
+
+// A Matcher searches a string for any of a fixed set of patterns using the
+// Aho-Corasick algorithm. Once built, a Matcher can be reused to search any
+// number of strings and is safe for concurrent use by multiple goroutines.
+type Matcher struct {
+	nodes    []acNode
+	patterns []string
+	opts     MatcherOpts
+}
+
+type acNode struct {
+	children map[byte]int // byte -> node index; empty (never nil) for leaves
+	fail     int          // failure link
+	output   int          // nearest proper failure ancestor that ends a pattern, plus 1; 0 means none
+	ends     []int        // indices of patterns ending at this node
+}
+
+// NewMatcher builds a Matcher that searches for any of patterns.
+// NewMatcher panics if patterns is empty or contains an empty pattern: the
+// automaton has no way to report a match of "" (it would have to occur at
+// every position), so rather than silently never matching it, it's
+// rejected up front.
+func NewMatcher(patterns []string, opts ...MatcherOpts) *Matcher {
+	if len(patterns) == 0 {
+		panic("strings: NewMatcher requires at least one pattern")
+	}
+	for _, p := range patterns {
+		if p == "" {
+			panic("strings: NewMatcher does not support an empty pattern")
+		}
+	}
+	var o MatcherOpts
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	m := &Matcher{
+		nodes:    []acNode{{children: make(map[byte]int)}}, // root
+		patterns: patterns,
+		opts:     o,
+	}
+	for pi, p := range patterns {
+		m.insert(p, pi)
+	}
+	m.buildFailureLinks()
+	return m
+}
+
+func (m *Matcher) foldByte(c byte) byte {
+	if m.opts.FoldCase && 'A' <= c && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+func (m *Matcher) insert(pattern string, patternIndex int) {
+	cur := 0
+	for i := 0; i < len(pattern); i++ {
+		c := m.foldByte(pattern[i])
+		next, ok := m.nodes[cur].children[c]
+		if !ok {
+			m.nodes = append(m.nodes, acNode{children: make(map[byte]int)})
+			next = len(m.nodes) - 1
+			m.nodes[cur].children[c] = next
+		}
+		cur = next
+	}
+	m.nodes[cur].ends = append(m.nodes[cur].ends, patternIndex)
+}
+
+// buildFailureLinks computes, for every node, the failure link (the longest
+// proper suffix of the node's path that is also a path from the root) via a
+// BFS over the trie, and derives each node's output link from its failure
+// ancestor.
+func (m *Matcher) buildFailureLinks() {
+	queue := make([]int, 0, len(m.nodes))
+	for c, child := range m.nodes[0].children {
+		m.nodes[child].fail = 0
+		queue = append(queue, child)
+		_ = c
+	}
+
+	for qi := 0; qi < len(queue); qi++ {
+		cur := queue[qi]
+		for c, child := range m.nodes[cur].children {
+			fail := m.nodes[cur].fail
+			for {
+				if n, ok := m.nodes[fail].children[c]; ok && n != child {
+					fail = n
+					break
+				}
+				if fail == 0 {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			m.nodes[child].fail = fail
+			if len(m.nodes[fail].ends) > 0 {
+				m.nodes[child].output = fail + 1
+			} else {
+				m.nodes[child].output = m.nodes[fail].output
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+func (m *Matcher) step(state int, c byte) int {
+	c = m.foldByte(c)
+	for {
+		if next, ok := m.nodes[state].children[c]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = m.nodes[state].fail
+	}
+}
+
+// IndexAny returns the byte offset of the leftmost-starting match of any
+// pattern in s, and the index of the matching pattern. It returns (-1, -1)
+// if none of the patterns occur in s.
+//
+// A longer pattern can start earlier in s but still be reported by the
+// automaton after a shorter, later-starting pattern, since Aho-Corasick
+// reports matches in order of where they end, not where they start,
+// (e.g. "aba" starts before "b" in "abaab" but "b" ends first). IndexAny
+// therefore scans all of s and keeps the match with the smallest start,
+// rather than returning as soon as anything matches.
+func (m *Matcher) IndexAny(s string) (pos int, patternIndex int) {
+	bestStart := -1
+	bestPattern := 0
+	state := 0
+	for i := 0; i < len(s); i++ {
+		state = m.step(state, s[i])
+		for n := state; n != 0; n = m.nodes[n].output - 1 {
+			if len(m.nodes[n].ends) == 0 {
+				if m.nodes[n].output == 0 {
+					break
+				}
+				continue
+			}
+			for _, pi := range m.nodes[n].ends {
+				start := i + 1 - len(m.patterns[pi])
+				if bestStart == -1 || start < bestStart {
+					bestStart, bestPattern = start, pi
+				}
+			}
+			if m.nodes[n].output == 0 {
+				break
+			}
+		}
+	}
+	if bestStart == -1 {
+		return -1, -1
+	}
+	return bestStart, bestPattern
+}
+
+// FindAll returns every match of every pattern in s, in the order in which
+// the matches end in s (among matches ending at the same byte, longer ones
+// come first). If the Matcher was built with MatcherOpts.LongestLeftmost, overlapping
+// matches are suppressed so that at most one (the longest) match starts at
+// any given position.
+func (m *Matcher) FindAll(s string) []Match {
+	var out []Match
+	state := 0
+	for i := 0; i < len(s); i++ {
+		state = m.step(state, s[i])
+		for n := state; n != 0; n = m.nodes[n].output - 1 {
+			if len(m.nodes[n].ends) == 0 {
+				if m.nodes[n].output == 0 {
+					break
+				}
+				continue
+			}
+			for _, pi := range m.nodes[n].ends {
+				end := i + 1
+				start := end - len(m.patterns[pi])
+				out = append(out, Match{Start: start, End: end, Pattern: pi})
+			}
+			if m.nodes[n].output == 0 {
+				break
+			}
+		}
+	}
+	if m.opts.LongestLeftmost {
+		out = longestLeftmost(out)
+	}
+	return out
+}
+
+// longestLeftmost keeps, for each start position, only the longest match,
+// and drops any match that starts inside another kept match.
+func longestLeftmost(matches []Match) []Match {
+	byStart := make(map[int]Match, len(matches))
+	for _, mt := range matches {
+		if best, ok := byStart[mt.Start]; !ok || mt.End > best.End {
+			byStart[mt.Start] = mt
+		}
+	}
+	starts := make([]int, 0, len(byStart))
+	for s := range byStart {
+		starts = append(starts, s)
+	}
+	// simple insertion sort; match counts are typically small
+	for i := 1; i < len(starts); i++ {
+		for j := i; j > 0 && starts[j-1] > starts[j]; j-- {
+			starts[j-1], starts[j] = starts[j], starts[j-1]
+		}
+	}
+	out := make([]Match, 0, len(starts))
+	end := -1
+	for _, s := range starts {
+		mt := byStart[s]
+		if mt.Start < end {
+			continue
+		}
+		out = append(out, mt)
+		end = mt.End
+	}
+	return out
+}
+
+// Count returns the number of non-overlapping matches of any pattern in s,
+// scanning left to right and, like [strings.Count], advancing past each
+// match as it is found rather than counting every overlapping occurrence
+// (that's what [Matcher.FindAll] is for).
+func (m *Matcher) Count(s string) int {
+	n := 0
+	for {
+		start, pi := m.IndexAny(s)
+		if start < 0 {
+			return n
+		}
+		n++
+		s = s[start+len(m.patterns[pi]):]
+	}
+}
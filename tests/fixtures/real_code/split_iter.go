@@ -0,0 +1,115 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strings
+
+import (
+	"iter"
+	"unicode"
+	"unicode/utf8"
+)
+
+// explodeSeq yields s split into its UTF-8 characters, one per yield, up to
+// a maximum of n characters (n < 0 means no limit); like [explode], the
+// final yielded string holds whatever of s is left once n-1 characters have
+// been yielded. It is the shared implementation behind [explode] and the
+// sep == "" case of [splitSeq].
+func explodeSeq(s string, n int, yield func(string) bool) {
+	l := utf8.RuneCountInString(s)
+	if n < 0 || n > l {
+		n = l
+	}
+	for i := 0; i < n-1; i++ {
+		_, size := utf8.DecodeRuneInString(s)
+		if !yield(s[:size]) {
+			return
+		}
+		s = s[size:]
+	}
+	if n > 0 {
+		yield(s)
+	}
+}
+
+// splitSeq is the iterator form of [genSplit]: it yields s split after each
+// instance of sep, including sepSave bytes of sep in each yielded substring,
+// up to n substrings (n < 0 means no limit, n == 0 yields nothing).
+func splitSeq(s, sep string, sepSave, n int) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		s, n := s, n // keep the iterator reusable: each range starts afresh
+		if n == 0 {
+			return
+		}
+		if sep == "" {
+			explodeSeq(s, n, yield)
+			return
+		}
+		if n < 0 {
+			n = Count(s, sep) + 1
+		}
+		for n != 1 {
+			i := Index(s, sep)
+			if i < 0 {
+				break
+			}
+			if !yield(s[:i+sepSave]) {
+				return
+			}
+			s = s[i+len(sep):]
+			n--
+		}
+		yield(s)
+	}
+}
+
+// SplitSeq returns an iterator over the substrings of s separated by sep.
+// The iterator yields the same strings that [Split] would return.
+func SplitSeq(s, sep string) iter.Seq[string] {
+	return splitSeq(s, sep, 0, -1)
+}
+
+// SplitAfterSeq returns an iterator over substrings of s split after each
+// instance of sep. The iterator yields the same strings that [SplitAfter]
+// would return.
+func SplitAfterSeq(s, sep string) iter.Seq[string] {
+	return splitSeq(s, sep, len(sep), -1)
+}
+
+// SplitNSeq returns an iterator over substrings of s separated by sep,
+// yielding the same strings that [SplitN] would return: n < 0 means no
+// limit on the number of substrings, n == 0 yields nothing, and n > 0
+// stops after n substrings with the last one holding the unsplit remainder.
+func SplitNSeq(s, sep string, n int) iter.Seq[string] {
+	return splitSeq(s, sep, 0, n)
+}
+
+// FieldsFuncSeq returns an iterator over substrings of s split around runs
+// of Unicode code points satisfying f, yielding the same strings that
+// [FieldsFunc] would return.
+func FieldsFuncSeq(s string, f func(rune) bool) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		start := -1 // start of the current field, or -1 if not in a field
+		for i, r := range s {
+			if f(r) {
+				if start >= 0 {
+					if !yield(s[start:i]) {
+						return
+					}
+					start = -1
+				}
+			} else if start < 0 {
+				start = i
+			}
+		}
+		if start >= 0 {
+			yield(s[start:])
+		}
+	}
+}
+
+// FieldsSeq returns an iterator over substrings of s split around runs of
+// Unicode whitespace, yielding the same strings that [Fields] would return.
+func FieldsSeq(s string) iter.Seq[string] {
+	return FieldsFuncSeq(s, unicode.IsSpace)
+}
@@ -0,0 +1,65 @@
+// Copyright 2009 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package strings_test
+
+import (
+	"bufio"
+	. "strings"
+	"testing"
+)
+
+func BenchmarkSplitSeq(b *testing.B) {
+	s := Repeat("field ", 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		for range SplitSeq(s, " ") {
+			n++
+		}
+	}
+}
+
+func BenchmarkFieldsSeq(b *testing.B) {
+	s := Repeat("field ", 1000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := 0
+		for range FieldsSeq(s) {
+			n++
+		}
+	}
+}
+
+// BenchmarkFieldsSeqVsScanner compares tokenizing large input with
+// FieldsSeq against the conventional bufio.Scanner approach, to
+// demonstrate that the iterator form is competitive without requiring an
+// up-front []string allocation.
+func BenchmarkFieldsSeqVsScanner(b *testing.B) {
+	s := Repeat("field ", 100000)
+
+	b.Run("FieldsSeq", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			n := 0
+			for range FieldsSeq(s) {
+				n++
+			}
+		}
+	})
+
+	b.Run("Scanner", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sc := bufio.NewScanner(NewReader(s))
+			sc.Split(bufio.ScanWords)
+			n := 0
+			for sc.Scan() {
+				n++
+			}
+		}
+	})
+}
@@ -21,19 +21,11 @@ const maxInt = int(^uint(0) >> 1)
 // one string per Unicode character up to a maximum of n (n < 0 means no limit).
 // Invalid UTF-8 bytes are sliced individually.
 func explode(s string, n int) []string {
-	l := utf8.RuneCountInString(s)
-	if n < 0 || n > l {
-		n = l
-	}
-	a := make([]string, n)
-	for i := 0; i < n-1; i++ {
-		_, size := utf8.DecodeRuneInString(s)
-		a[i] = s[:size]
-		s = s[size:]
-	}
-	if n > 0 {
-		a[n-1] = s
-	}
+	var a []string
+	explodeSeq(s, n, func(part string) bool {
+		a = append(a, part)
+		return true
+	})
 	return a
 }
 
@@ -58,6 +50,28 @@ func Count(s, substr string) int {
 	}
 }
 
+// Index returns the index of the first instance of substr in s, or -1 if
+// substr is not present in s.
+func Index(s, substr string) int {
+	n := len(substr)
+	switch {
+	case n == 0:
+		return 0
+	case n == 1:
+		return IndexByte(s, substr[0])
+	case n == len(s):
+		if substr == s {
+			return 0
+		}
+		return -1
+	case n > len(s):
+		return -1
+	case n >= bytealg.MinTwoWay:
+		return bytealg.IndexTwoWayString(s, substr)
+	}
+	return bytealg.IndexRabinKarp(s, substr)
+}
+
 // Contains reports whether substr is within s.
 func Contains(s, substr string) bool {
 	return Index(s, substr) >= 0
@@ -93,6 +107,8 @@ func LastIndex(s, substr string) int {
 		return -1
 	case n > len(s):
 		return -1
+	case n >= bytealg.MinTwoWay:
+		return bytealg.LastIndexTwoWayString(s, substr)
 	}
 	return bytealg.LastIndexRabinKarp(s, substr)
 }
@@ -270,31 +286,13 @@ func LastIndexByte(s string, c byte) int {
 
 // Generic split: splits after each instance of sep,
 // including sepSave bytes of sep in the subarrays.
+//
+// genSplit is a thin wrapper that collects from splitSeq, which holds the
+// actual splitting logic shared with the iterator-returning *Seq functions.
 func genSplit(s, sep string, sepSave, n int) []string {
-	if n == 0 {
-		return nil
-	}
-	if sep == "" {
-		return explode(s, n)
-	}
-	if n < 0 {
-		n = Count(s, sep) + 1
+	var a []string
+	for part := range splitSeq(s, sep, sepSave, n) {
+		a = append(a, part)
 	}
-
-	if n > len(s)+1 {
-		n = len(s) + 1
-	}
-	a := make([]string, n)
-	n--
-	i := 0
-	for i < n {
-		m := Index(s, sep)
-		if m < 0 {
-			break
-		}
-		a[i] = s[:m+sepSave]
-		s = s[m+len(sep):]
-		i++
-	}
-	a[i] = s
-	return a[:i+1]
+	return a
+}